@@ -0,0 +1,94 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package job
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"time"
+
+	"github.com/nuclio/errors"
+	"github.com/nuclio/logger"
+)
+
+// runManifest summarizes a single run, packaged alongside its captured log into the run's archive.
+type runManifest struct {
+	RunID     string            `json:"runId"`
+	TriggerID string            `json:"triggerId"`
+	Attempts  int               `json:"attempts"`
+	StartTime time.Time         `json:"startTime"`
+	Elapsed   time.Duration     `json:"elapsed"`
+	Result    *CompletionResult `json:"result,omitempty"`
+}
+
+// archiveSink publishes a run's archive and returns a URL downstream systems can use to fetch it.
+type archiveSink interface {
+	Publish(runID string, archive []byte) (string, error)
+}
+
+// newArchiveSink builds the archiveSink configured by config, or nil if log archiving is disabled.
+func newArchiveSink(parentLogger logger.Logger, config LogArchiveConfiguration) (archiveSink, error) {
+	switch config.Sink {
+	case LogArchiveSinkNone:
+		return nil, nil
+	case LogArchiveSinkLocal:
+		return newLocalArchiveSink(parentLogger, config), nil
+	case LogArchiveSinkHTTP:
+		return newHTTPArchiveSink(config), nil
+	case LogArchiveSinkS3:
+		return newS3ArchiveSink(config)
+	default:
+		return nil, errors.Errorf("Unknown log archive sink type: %s", config.Sink)
+	}
+}
+
+// buildRunArchive packages manifest and the captured log into a .tar.gz, as published by DevLake-style
+// downloadable pipeline log archives.
+func buildRunArchive(manifest runManifest, logBytes []byte) ([]byte, error) {
+	manifestBytes, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to marshal run manifest")
+	}
+
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	if err := writeTarFile(tarWriter, "manifest.json", manifestBytes); err != nil {
+		return nil, err
+	}
+	if err := writeTarFile(tarWriter, "run.log", logBytes); err != nil {
+		return nil, err
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return nil, errors.Wrap(err, "Failed to close run archive")
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return nil, errors.Wrap(err, "Failed to close run archive")
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeTarFile(tarWriter *tar.Writer, name string, content []byte) error {
+	if err := tarWriter.WriteHeader(&tar.Header{
+		Name: name,
+		Mode: 0644,
+		Size: int64(len(content)),
+	}); err != nil {
+		return errors.Wrapf(err, "Failed to write %s header", name)
+	}
+
+	if _, err := tarWriter.Write(content); err != nil {
+		return errors.Wrapf(err, "Failed to write %s content", name)
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package job
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBuildRunArchiveContainsManifestAndLog(t *testing.T) {
+	manifest := runManifest{
+		RunID:     "run-1",
+		TriggerID: "trigger-1",
+		Attempts:  2,
+	}
+
+	archive, err := buildRunArchive(manifest, []byte("log line\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	gzipReader, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		t.Fatalf("failed to open gzip reader: %s", err)
+	}
+	tarReader := tar.NewReader(gzipReader)
+
+	names := map[string][]byte{}
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to read tar entry: %s", err)
+		}
+
+		content, err := io.ReadAll(tarReader)
+		if err != nil {
+			t.Fatalf("failed to read tar entry content: %s", err)
+		}
+		names[header.Name] = content
+	}
+
+	if _, ok := names["manifest.json"]; !ok {
+		t.Error("expected archive to contain manifest.json")
+	}
+	if string(names["run.log"]) != "log line\n" {
+		t.Errorf("expected run.log to contain the captured log, got %q", names["run.log"])
+	}
+}
+
+func TestNewArchiveSinkNoneReturnsNil(t *testing.T) {
+	sink, err := newArchiveSink(&fakeLogger{}, LogArchiveConfiguration{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if sink != nil {
+		t.Error("expected a nil sink when LogArchive is not configured")
+	}
+}
+
+func TestLocalArchiveSinkPublish(t *testing.T) {
+	sink := newLocalArchiveSink(&fakeLogger{}, LogArchiveConfiguration{LocalPath: t.TempDir()})
+
+	url, err := sink.Publish("run-1", []byte("archive-content"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if url == "" {
+		t.Error("expected a non-empty archive URL")
+	}
+}
+
+func TestLocalArchiveSinkPrunesExpiredArchives(t *testing.T) {
+	dir := t.TempDir()
+	sink := newLocalArchiveSink(&fakeLogger{}, LogArchiveConfiguration{LocalPath: dir, Retention: time.Millisecond})
+
+	if _, err := sink.Publish("old-run", []byte("stale")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	if _, err := sink.Publish("new-run", []byte("fresh")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "old-run.tar.gz")); !os.IsNotExist(err) {
+		t.Errorf("expected old-run's archive to be pruned, stat returned %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "new-run.tar.gz")); err != nil {
+		t.Errorf("expected new-run's archive to still exist, got %s", err)
+	}
+}
@@ -0,0 +1,173 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package job
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/nuclio/errors"
+	"github.com/nuclio/logger"
+)
+
+// localArchiveSink writes run archives to a local directory, for single-node deployments
+// or development.
+type localArchiveSink struct {
+	path      string
+	retention time.Duration
+	logger    logger.Logger
+}
+
+func newLocalArchiveSink(parentLogger logger.Logger, config LogArchiveConfiguration) *localArchiveSink {
+	return &localArchiveSink{
+		path:      config.LocalPath,
+		retention: config.Retention,
+		logger:    parentLogger,
+	}
+}
+
+func (s *localArchiveSink) Publish(runID string, archive []byte) (string, error) {
+	if err := os.MkdirAll(s.path, 0755); err != nil {
+		return "", errors.Wrap(err, "Failed to create log archive directory")
+	}
+
+	archivePath := filepath.Join(s.path, runID+".tar.gz")
+	if err := os.WriteFile(archivePath, archive, 0644); err != nil {
+		return "", errors.Wrap(err, "Failed to write log archive")
+	}
+
+	s.pruneExpired()
+
+	return "file://" + archivePath, nil
+}
+
+// pruneExpired removes previously published archives older than s.retention. A failure to list
+// the directory or remove an individual archive is logged and otherwise ignored: it must never
+// fail Publish.
+func (s *localArchiveSink) pruneExpired() {
+	if s.retention <= 0 {
+		return
+	}
+
+	entries, err := os.ReadDir(s.path)
+	if err != nil {
+		s.logger.WarnWith("Failed to list log archive directory for retention pruning", "error", err)
+		return
+	}
+
+	cutoff := time.Now().Add(-s.retention)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".tar.gz") {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(cutoff) {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(s.path, entry.Name())); err != nil {
+			s.logger.WarnWith("Failed to prune expired log archive", "name", entry.Name(), "error", err)
+		}
+	}
+}
+
+// httpArchiveSink PUTs run archives to an HTTP endpoint (e.g. a pre-signed upload URL).
+type httpArchiveSink struct {
+	baseURL string
+	client  *http.Client
+}
+
+func newHTTPArchiveSink(config LogArchiveConfiguration) *httpArchiveSink {
+	return &httpArchiveSink{
+		baseURL: config.HTTPURL,
+		client:  &http.Client{},
+	}
+}
+
+func (s *httpArchiveSink) Publish(runID string, archive []byte) (string, error) {
+	url := fmt.Sprintf("%s/%s.tar.gz", s.baseURL, runID)
+
+	request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(archive))
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to build log archive upload request")
+	}
+	request.Header.Set("Content-Type", "application/gzip")
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to upload log archive")
+	}
+	defer response.Body.Close() // nolint: errcheck
+
+	if response.StatusCode >= 300 {
+		return "", errors.Errorf("Log archive upload returned status %d", response.StatusCode)
+	}
+
+	return url, nil
+}
+
+// s3RetentionTagKey is set to an RFC3339 expiry timestamp on every archive object uploaded with
+// a non-zero retention. S3 has no native per-object TTL, so pruning still requires a bucket
+// lifecycle rule that expires objects carrying this tag past its value.
+const s3RetentionTagKey = "nuclio-archive-retention-until"
+
+// s3ArchiveSink uploads run archives to an S3-compatible object store.
+type s3ArchiveSink struct {
+	client    *minio.Client
+	bucket    string
+	prefix    string
+	retention time.Duration
+}
+
+func newS3ArchiveSink(config LogArchiveConfiguration) (*s3ArchiveSink, error) {
+	client, err := minio.New(config.S3Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(config.S3AccessKeyID, config.S3SecretAccessKey, ""),
+		Secure: config.S3UseSSL,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create S3 client for log archive sink")
+	}
+
+	return &s3ArchiveSink{
+		client:    client,
+		bucket:    config.S3Bucket,
+		prefix:    config.S3Prefix,
+		retention: config.Retention,
+	}, nil
+}
+
+func (s *s3ArchiveSink) Publish(runID string, archive []byte) (string, error) {
+	objectName := fmt.Sprintf("%s%s.tar.gz", s.prefix, runID)
+
+	options := minio.PutObjectOptions{ContentType: "application/gzip"}
+	if s.retention > 0 {
+		options.UserTags = map[string]string{
+			s3RetentionTagKey: time.Now().Add(s.retention).UTC().Format(time.RFC3339),
+		}
+	}
+
+	_, err := s.client.PutObject(context.Background(),
+		s.bucket,
+		objectName,
+		bytes.NewReader(archive),
+		int64(len(archive)),
+		options)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to upload log archive to S3")
+	}
+
+	return fmt.Sprintf("s3://%s/%s", s.bucket, objectName), nil
+}
@@ -0,0 +1,114 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package job
+
+import (
+	"encoding/json"
+
+	"github.com/nuclio/errors"
+	"github.com/nuclio/nuclio-sdk-go"
+)
+
+// artifactManifestHeader is the response header carrying the JSON-encoded list of produced
+// outputs, as emitted by user code that wants them tracked.
+const artifactManifestHeader = "X-Nuclio-Artifacts"
+
+// artifactManifestEntry is a single produced output, as declared by the function in the
+// X-Nuclio-Artifacts response header.
+type artifactManifestEntry struct {
+	Name string                 `json:"name"`
+	URI  string                 `json:"uri"`
+	MD5  string                 `json:"md5"`
+	Size int64                  `json:"size"`
+	Spec map[string]interface{} `json:"spec"`
+}
+
+// recordedArtifact is what gets attached to the complete control message for each output
+// successfully registered with the artifact service.
+type recordedArtifact struct {
+	Name       string `json:"name"`
+	URI        string `json:"uri"`
+	ArtifactID string `json:"artifactId"`
+}
+
+// ArtifactSink registers a produced output with an external artifact service.
+type ArtifactSink interface {
+	Register(project, domain, version string, entry artifactManifestEntry) (string, error)
+}
+
+// newArtifactSink builds the ArtifactSink configured by config, or nil if artifact
+// recording is disabled.
+func newArtifactSink(config ArtifactsConfiguration) (ArtifactSink, error) {
+	switch config.Sink {
+	case ArtifactSinkTypeNone:
+		return nil, nil
+	case ArtifactSinkTypeHTTP:
+		return newHTTPArtifactSink(config), nil
+	case ArtifactSinkTypeS3:
+		return newS3ArtifactSink(config)
+	default:
+		return nil, errors.Errorf("Unknown artifact sink type: %s", config.Sink)
+	}
+}
+
+// extractArtifactManifest reads the X-Nuclio-Artifacts header off a nuclio.Response, if present.
+func extractArtifactManifest(response interface{}) ([]artifactManifestEntry, error) {
+	typedResponse, ok := response.(nuclio.Response)
+	if !ok {
+		return nil, nil
+	}
+
+	raw, found := typedResponse.Headers[artifactManifestHeader]
+	if !found {
+		return nil, nil
+	}
+
+	var manifestJSON string
+	switch typed := raw.(type) {
+	case string:
+		manifestJSON = typed
+	case []byte:
+		manifestJSON = string(typed)
+	default:
+		return nil, errors.Errorf("Unexpected type for %s header: %T", artifactManifestHeader, raw)
+	}
+
+	var entries []artifactManifestEntry
+	if err := json.Unmarshal([]byte(manifestJSON), &entries); err != nil {
+		return nil, errors.Wrap(err, "Failed to parse artifact manifest")
+	}
+
+	return entries, nil
+}
+
+// validateArtifactChecksum checks a produced artifact's manifest entry against the checksum
+// type declared for it in spec, rejecting an entry that doesn't carry the checksum it was
+// declared to.
+func validateArtifactChecksum(spec ArtifactSpec, entry artifactManifestEntry) error {
+	switch spec.ChecksumType {
+	case ChecksumTypeNone:
+		return nil
+	case ChecksumTypeMD5:
+		if entry.MD5 == "" {
+			return errors.Errorf("Artifact %q declares checksum type %q but its manifest entry has no md5",
+				spec.Name, spec.ChecksumType)
+		}
+		return nil
+	default:
+		return errors.Errorf("Artifact %q declares unknown checksum type %q", spec.Name, spec.ChecksumType)
+	}
+}
+
+// declaredArtifactsByName indexes the configured outputs by name for manifest validation.
+func declaredArtifactsByName(specs []ArtifactSpec) map[string]ArtifactSpec {
+	byName := make(map[string]ArtifactSpec, len(specs))
+	for _, spec := range specs {
+		byName[spec.Name] = spec
+	}
+
+	return byName
+}
@@ -0,0 +1,77 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package job
+
+import (
+	"testing"
+
+	"github.com/nuclio/nuclio-sdk-go"
+)
+
+func TestExtractArtifactManifestParsesHeader(t *testing.T) {
+	response := nuclio.Response{
+		Headers: map[string]interface{}{
+			artifactManifestHeader: `[{"name":"model","uri":"s3://bucket/model.bin","md5":"abc","size":1024}]`,
+		},
+	}
+
+	entries, err := extractArtifactManifest(response)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	if entries[0].Name != "model" || entries[0].URI != "s3://bucket/model.bin" {
+		t.Errorf("unexpected entry: %+v", entries[0])
+	}
+}
+
+func TestExtractArtifactManifestNoHeaderReturnsNil(t *testing.T) {
+	response := nuclio.Response{}
+
+	entries, err := extractArtifactManifest(response)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if entries != nil {
+		t.Errorf("expected nil entries, got %+v", entries)
+	}
+}
+
+func TestValidateArtifactChecksumRejectsMissingMD5(t *testing.T) {
+	spec := ArtifactSpec{Name: "model", ChecksumType: ChecksumTypeMD5}
+
+	if err := validateArtifactChecksum(spec, artifactManifestEntry{Name: "model", MD5: "abc"}); err != nil {
+		t.Errorf("expected a present md5 to pass validation, got %s", err)
+	}
+	if err := validateArtifactChecksum(spec, artifactManifestEntry{Name: "model"}); err == nil {
+		t.Error("expected a missing md5 to fail validation when ChecksumType is md5")
+	}
+}
+
+func TestValidateArtifactChecksumNoneAcceptsAnyEntry(t *testing.T) {
+	spec := ArtifactSpec{Name: "model"}
+
+	if err := validateArtifactChecksum(spec, artifactManifestEntry{Name: "model"}); err != nil {
+		t.Errorf("expected no checksum type to skip validation, got %s", err)
+	}
+}
+
+func TestDeclaredArtifactsByName(t *testing.T) {
+	byName := declaredArtifactsByName([]ArtifactSpec{
+		{Name: "model"},
+		{Name: "metrics"},
+	})
+
+	if _, ok := byName["model"]; !ok {
+		t.Error("expected model to be declared")
+	}
+	if _, ok := byName["unknown"]; ok {
+		t.Error("expected unknown to not be declared")
+	}
+}
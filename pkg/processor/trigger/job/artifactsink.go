@@ -0,0 +1,144 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package job
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/nuclio/errors"
+)
+
+// httpArtifactSink registers produced outputs with an HTTP artifact service, borrowing the
+// artifact-registration pattern from Flyte's execution manager.
+type httpArtifactSink struct {
+	url    string
+	client *http.Client
+}
+
+func newHTTPArtifactSink(config ArtifactsConfiguration) *httpArtifactSink {
+	return &httpArtifactSink{
+		url:    config.HTTPURL,
+		client: &http.Client{},
+	}
+}
+
+type httpArtifactRegistration struct {
+	Project string                 `json:"project"`
+	Domain  string                 `json:"domain"`
+	Version string                 `json:"version"`
+	Name    string                 `json:"name"`
+	URI     string                 `json:"uri"`
+	MD5     string                 `json:"md5"`
+	Size    int64                  `json:"size"`
+	Spec    map[string]interface{} `json:"spec"`
+}
+
+type httpArtifactRegistrationResponse struct {
+	ID string `json:"id"`
+}
+
+func (s *httpArtifactSink) Register(project, domain, version string, entry artifactManifestEntry) (string, error) {
+	body, err := json.Marshal(httpArtifactRegistration{
+		Project: project,
+		Domain:  domain,
+		Version: version,
+		Name:    entry.Name,
+		URI:     entry.URI,
+		MD5:     entry.MD5,
+		Size:    entry.Size,
+		Spec:    entry.Spec,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to marshal artifact registration")
+	}
+
+	request, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to build artifact registration request")
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to register artifact")
+	}
+	defer response.Body.Close() // nolint: errcheck
+
+	if response.StatusCode >= 300 {
+		return "", errors.Errorf("Artifact registration returned status %d", response.StatusCode)
+	}
+
+	var registrationResponse httpArtifactRegistrationResponse
+	if err := json.NewDecoder(response.Body).Decode(&registrationResponse); err != nil {
+		return "", errors.Wrap(err, "Failed to decode artifact registration response")
+	}
+
+	return registrationResponse.ID, nil
+}
+
+// s3ArtifactSink records produced outputs as small JSON documents in an S3-compatible bucket,
+// for deployments without a dedicated artifact service.
+type s3ArtifactSink struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+func newS3ArtifactSink(config ArtifactsConfiguration) (*s3ArtifactSink, error) {
+	client, err := minio.New(config.S3Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(config.S3AccessKeyID, config.S3SecretAccessKey, ""),
+		Secure: config.S3UseSSL,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create S3 client for artifact sink")
+	}
+
+	return &s3ArtifactSink{
+		client: client,
+		bucket: config.S3Bucket,
+		prefix: config.S3Prefix,
+	}, nil
+}
+
+func (s *s3ArtifactSink) Register(project, domain, version string, entry artifactManifestEntry) (string, error) {
+	objectName := fmt.Sprintf("%s%s/%s/%s/%s.json", s.prefix, project, domain, version, entry.Name)
+
+	body, err := json.Marshal(struct {
+		Project string                 `json:"project"`
+		Domain  string                 `json:"domain"`
+		Version string                 `json:"version"`
+		Entry   artifactManifestEntry  `json:"entry"`
+		Spec    map[string]interface{} `json:"spec"`
+	}{
+		Project: project,
+		Domain:  domain,
+		Version: version,
+		Entry:   entry,
+		Spec:    entry.Spec,
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to marshal artifact record")
+	}
+
+	_, err = s.client.PutObject(context.Background(),
+		s.bucket,
+		objectName,
+		bytes.NewReader(body),
+		int64(len(body)),
+		minio.PutObjectOptions{ContentType: "application/json"})
+	if err != nil {
+		return "", errors.Wrap(err, "Failed to register artifact in S3")
+	}
+
+	return fmt.Sprintf("s3://%s/%s", s.bucket, objectName), nil
+}
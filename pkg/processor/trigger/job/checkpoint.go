@@ -0,0 +1,167 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package job
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nuclio/errors"
+	"github.com/nuclio/nuclio/pkg/functionconfig"
+)
+
+// terminalRun records a run that finished but whose terminal control message may not have
+// reached its consumers yet (e.g. the processor restarted right after the run completed).
+type terminalRun struct {
+	Result       *CompletionResult  `json:"result,omitempty"`
+	Attempts     int                `json:"attempts"`
+	ArchiveURL   string             `json:"archiveUrl,omitempty"`
+	Artifacts    []recordedArtifact `json:"artifacts,omitempty"`
+	Acknowledged bool               `json:"acknowledged"`
+}
+
+// checkpointState is the durable state a job trigger needs to resume after a processor
+// restart rather than re-running from scratch: how far a retrying attempt got, where a
+// periodic schedule left off, and which runs finished but weren't acknowledged yet.
+type checkpointState struct {
+	Attempt              int                     `json:"attempt,omitempty"`
+	LastSubmittedEventID string                  `json:"lastSubmittedEventId,omitempty"`
+	BackoffCursor        time.Duration           `json:"backoffCursor,omitempty"`
+	ScheduleCursor       time.Time               `json:"scheduleCursor,omitempty"`
+	RunsLaunched         int                     `json:"runsLaunched,omitempty"`
+
+	// RunSeq is the highest run id sequence number handed out so far. It is restored into
+	// job.runSeq on resume so a restarted processor never hands out a runID that was already
+	// used (and possibly still in flight or unacknowledged) before the restart.
+	RunSeq int64 `json:"runSeq,omitempty"`
+
+	// InFlightRuns maps a runID to the time it was launched, for every run that was in flight
+	// the last time this trigger checkpointed. A run only leaves this set once it finalizes;
+	// a crash while an entry is still here means the run's outcome was lost along with it, and
+	// finalizeLostInFlightRuns reports it as such on resume rather than leaving it untracked.
+	InFlightRuns map[string]time.Time    `json:"inFlightRuns,omitempty"`
+	TerminalRuns map[string]*terminalRun `json:"terminalRuns,omitempty"`
+}
+
+func newCheckpointState() *checkpointState {
+	return &checkpointState{
+		InFlightRuns: map[string]time.Time{},
+		TerminalRuns: map[string]*terminalRun{},
+	}
+}
+
+// ensureMaps fills in nil maps left by decoding a checkpoint that predates one of them, or
+// that simply omitted an empty one.
+func (s *checkpointState) ensureMaps() {
+	if s.InFlightRuns == nil {
+		s.InFlightRuns = map[string]time.Time{}
+	}
+	if s.TerminalRuns == nil {
+		s.TerminalRuns = map[string]*terminalRun{}
+	}
+}
+
+// clone returns a deep copy of state via a JSON round-trip, cheap enough given how
+// infrequently checkpoints are persisted.
+func (s *checkpointState) clone() *checkpointState {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return newCheckpointState()
+	}
+
+	clone := newCheckpointState()
+	if err := json.Unmarshal(data, clone); err != nil {
+		return newCheckpointState()
+	}
+
+	return clone
+}
+
+// decodeCheckpoint parses a functionconfig.Checkpoint into a checkpointState. A nil or empty
+// checkpoint yields a fresh state.
+func decodeCheckpoint(checkpoint functionconfig.Checkpoint) (*checkpointState, error) {
+	if checkpoint == nil || *checkpoint == "" {
+		return newCheckpointState(), nil
+	}
+
+	state := newCheckpointState()
+	if err := json.Unmarshal([]byte(*checkpoint), state); err != nil {
+		return nil, errors.Wrap(err, "Failed to decode job checkpoint")
+	}
+	state.ensureMaps()
+
+	return state, nil
+}
+
+// encodeCheckpoint serializes a checkpointState into a functionconfig.Checkpoint.
+func encodeCheckpoint(state *checkpointState) (functionconfig.Checkpoint, error) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to encode job checkpoint")
+	}
+
+	encoded := string(data)
+	return &encoded, nil
+}
+
+// CheckpointStore persists a job trigger's checkpointState across processor restarts. When
+// not configured, the trigger relies instead on the runtime's existing checkpoint mechanism:
+// the functionconfig.Checkpoint value threaded through Start and returned from Stop.
+type CheckpointStore interface {
+	Save(id string, state *checkpointState) error
+	Load(id string) (*checkpointState, error)
+}
+
+// fileCheckpointStore persists checkpoints as JSON files on the local filesystem.
+type fileCheckpointStore struct {
+	path string
+}
+
+func newFileCheckpointStore(path string) *fileCheckpointStore {
+	return &fileCheckpointStore{path: path}
+}
+
+func (s *fileCheckpointStore) checkpointPath(id string) string {
+	return filepath.Join(s.path, id+".checkpoint.json")
+}
+
+func (s *fileCheckpointStore) Save(id string, state *checkpointState) error {
+	if err := os.MkdirAll(s.path, 0755); err != nil {
+		return errors.Wrap(err, "Failed to create checkpoint directory")
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return errors.Wrap(err, "Failed to marshal checkpoint")
+	}
+
+	if err := os.WriteFile(s.checkpointPath(id), data, 0644); err != nil {
+		return errors.Wrap(err, "Failed to write checkpoint file")
+	}
+
+	return nil
+}
+
+func (s *fileCheckpointStore) Load(id string) (*checkpointState, error) {
+	data, err := os.ReadFile(s.checkpointPath(id))
+	if os.IsNotExist(err) {
+		return newCheckpointState(), nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to read checkpoint file")
+	}
+
+	state := newCheckpointState()
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, errors.Wrap(err, "Failed to unmarshal checkpoint file")
+	}
+	state.ensureMaps()
+
+	return state, nil
+}
@@ -0,0 +1,91 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package job
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEncodeDecodeCheckpointRoundTrips(t *testing.T) {
+	state := newCheckpointState()
+	state.Attempt = 2
+	state.BackoffCursor = 4 * time.Second
+	state.TerminalRuns["run-1"] = &terminalRun{
+		Attempts:     1,
+		Acknowledged: true,
+	}
+	state.InFlightRuns["run-2"] = time.Unix(1700000000, 0).UTC()
+
+	checkpoint, err := encodeCheckpoint(state)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	decoded, err := decodeCheckpoint(checkpoint)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if decoded.Attempt != 2 {
+		t.Errorf("expected attempt 2, got %d", decoded.Attempt)
+	}
+	if decoded.BackoffCursor != 4*time.Second {
+		t.Errorf("expected backoff cursor 4s, got %s", decoded.BackoffCursor)
+	}
+	if run, ok := decoded.TerminalRuns["run-1"]; !ok || !run.Acknowledged {
+		t.Errorf("expected run-1 to be decoded as acknowledged, got %+v", decoded.TerminalRuns)
+	}
+	if _, ok := decoded.InFlightRuns["run-2"]; !ok {
+		t.Errorf("expected run-2 to still be recorded as in flight, got %+v", decoded.InFlightRuns)
+	}
+}
+
+func TestDecodeCheckpointNilYieldsFreshState(t *testing.T) {
+	state, err := decodeCheckpoint(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if state.Attempt != 0 || len(state.TerminalRuns) != 0 || len(state.InFlightRuns) != 0 {
+		t.Errorf("expected a fresh state, got %+v", state)
+	}
+}
+
+func TestFileCheckpointStoreSaveLoadRoundTrips(t *testing.T) {
+	store := newFileCheckpointStore(t.TempDir())
+
+	state := newCheckpointState()
+	state.Attempt = 3
+	state.TerminalRuns["run-1"] = &terminalRun{Attempts: 3, Acknowledged: false}
+
+	if err := store.Save("trigger-1", state); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	loaded, err := store.Load("trigger-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if loaded.Attempt != 3 {
+		t.Errorf("expected attempt 3, got %d", loaded.Attempt)
+	}
+	if loaded.TerminalRuns["run-1"].Acknowledged {
+		t.Error("expected run-1 to still be unacknowledged")
+	}
+}
+
+func TestFileCheckpointStoreLoadMissingReturnsFreshState(t *testing.T) {
+	store := newFileCheckpointStore(t.TempDir())
+
+	state, err := store.Load("does-not-exist")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if state.Attempt != 0 {
+		t.Errorf("expected a fresh state, got %+v", state)
+	}
+}
@@ -0,0 +1,85 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package job
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/nuclio/nuclio-sdk-go"
+)
+
+// Completion stages, identifying at which point of the submit/process/response pipeline a job run failed.
+const (
+	CompletionStageSubmit   = "submit"
+	CompletionStageProcess  = "process"
+	CompletionStageResponse = "response"
+
+	// CompletionStageLost marks a run that was still in flight the last time the trigger
+	// checkpointed and was never finalized, e.g. because the processor crashed rather than
+	// stopping cleanly. Its actual outcome is unknown.
+	CompletionStageLost = "lost"
+)
+
+// CompletionResult carries structured information about a failed job run, distinguishing
+// transient infrastructure errors (submit, process) from user-code errors (a non-200 response),
+// modeled on Bacalhau's APIError.
+type CompletionResult struct {
+	Stage          string        `json:"stage"`
+	HTTPStatusCode int           `json:"httpStatusCode,omitempty"`
+	Code           string        `json:"code,omitempty"`
+	Message        string        `json:"message,omitempty"`
+	Error          string        `json:"error,omitempty"`
+	WorkerID       string        `json:"workerId,omitempty"`
+	Elapsed        time.Duration `json:"elapsed"`
+	Attempt        int           `json:"attempt"`
+}
+
+// newCompletionResult classifies the outcome of a submission attempt into a structured CompletionResult.
+// It returns nil when the attempt completed successfully (no submit/process error and a 200 response).
+func newCompletionResult(response interface{},
+	submitError error,
+	processError error,
+	workerID string,
+	elapsed time.Duration,
+	attempt int) *CompletionResult {
+
+	switch {
+	case submitError != nil:
+		return &CompletionResult{
+			Stage:    CompletionStageSubmit,
+			Message:  "Failed to allocate worker and submit event",
+			Error:    submitError.Error(),
+			WorkerID: workerID,
+			Elapsed:  elapsed,
+			Attempt:  attempt,
+		}
+	case processError != nil:
+		return &CompletionResult{
+			Stage:    CompletionStageProcess,
+			Message:  "Worker failed to process event",
+			Error:    processError.Error(),
+			WorkerID: workerID,
+			Elapsed:  elapsed,
+			Attempt:  attempt,
+		}
+	}
+
+	if typedResponse, ok := response.(nuclio.Response); ok && typedResponse.StatusCode != http.StatusOK {
+		return &CompletionResult{
+			Stage:          CompletionStageResponse,
+			HTTPStatusCode: typedResponse.StatusCode,
+			Code:           http.StatusText(typedResponse.StatusCode),
+			Message:        "Function returned a non-200 response",
+			WorkerID:       workerID,
+			Elapsed:        elapsed,
+			Attempt:        attempt,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package job
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nuclio/nuclio-sdk-go"
+)
+
+func TestNewCompletionResultSubmitError(t *testing.T) {
+	submitError := errors.New("failed to allocate worker")
+
+	result := newCompletionResult(nil, submitError, nil, "worker-0", time.Second, 1)
+	if result == nil {
+		t.Fatal("expected a non-nil completion result")
+	}
+	if result.Stage != CompletionStageSubmit {
+		t.Errorf("expected stage %q, got %q", CompletionStageSubmit, result.Stage)
+	}
+	if result.Error != submitError.Error() {
+		t.Errorf("expected error %q, got %q", submitError.Error(), result.Error)
+	}
+	if result.WorkerID != "worker-0" {
+		t.Errorf("expected worker id %q, got %q", "worker-0", result.WorkerID)
+	}
+}
+
+func TestNewCompletionResultProcessError(t *testing.T) {
+	processError := errors.New("worker panicked")
+
+	result := newCompletionResult(nil, nil, processError, "worker-1", time.Second, 2)
+	if result == nil {
+		t.Fatal("expected a non-nil completion result")
+	}
+	if result.Stage != CompletionStageProcess {
+		t.Errorf("expected stage %q, got %q", CompletionStageProcess, result.Stage)
+	}
+	if result.Error != processError.Error() {
+		t.Errorf("expected error %q, got %q", processError.Error(), result.Error)
+	}
+	if result.Attempt != 2 {
+		t.Errorf("expected attempt 2, got %d", result.Attempt)
+	}
+}
+
+func TestNewCompletionResultNon200Response(t *testing.T) {
+	response := nuclio.Response{
+		StatusCode: 500,
+	}
+
+	result := newCompletionResult(response, nil, nil, "worker-2", time.Second, 1)
+	if result == nil {
+		t.Fatal("expected a non-nil completion result")
+	}
+	if result.Stage != CompletionStageResponse {
+		t.Errorf("expected stage %q, got %q", CompletionStageResponse, result.Stage)
+	}
+	if result.HTTPStatusCode != 500 {
+		t.Errorf("expected status code 500, got %d", result.HTTPStatusCode)
+	}
+}
+
+func TestNewCompletionResultSuccess(t *testing.T) {
+	response := nuclio.Response{
+		StatusCode: 200,
+	}
+
+	result := newCompletionResult(response, nil, nil, "worker-3", time.Second, 1)
+	if result != nil {
+		t.Fatalf("expected a nil completion result, got %+v", result)
+	}
+}
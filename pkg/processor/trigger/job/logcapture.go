@@ -0,0 +1,139 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package job
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nuclio/logger"
+)
+
+// runLogBuffer accumulates the log lines emitted during a single run, up to MaxSizeBytes.
+// Once the cap is reached, further lines are dropped rather than growing without bound.
+type runLogBuffer struct {
+	mu        sync.Mutex
+	buf       bytes.Buffer
+	maxSize   int64
+	truncated bool
+}
+
+func newRunLogBuffer(maxSize int64) *runLogBuffer {
+	return &runLogBuffer{maxSize: maxSize}
+}
+
+func (b *runLogBuffer) writeLine(level string, message string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.truncated {
+		return
+	}
+
+	line := fmt.Sprintf("%s [%s] %s\n", time.Now().UTC().Format(time.RFC3339Nano), level, message)
+
+	if b.maxSize > 0 && int64(b.buf.Len()+len(line)) > b.maxSize {
+		b.truncated = true
+		b.buf.WriteString("... log truncated, size cap reached ...\n")
+		return
+	}
+
+	b.buf.WriteString(line)
+}
+
+// Bytes returns a snapshot of the buffered log content.
+func (b *runLogBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]byte(nil), b.buf.Bytes()...)
+}
+
+// capturingLogger tees every log call through to a runLogBuffer in addition to the
+// wrapped logger, so a run's worker output can be archived independently of the
+// processor's main log stream.
+type capturingLogger struct {
+	logger.Logger
+	buffer *runLogBuffer
+}
+
+func newCapturingLogger(parent logger.Logger, buffer *runLogBuffer) *capturingLogger {
+	return &capturingLogger{
+		Logger: parent,
+		buffer: buffer,
+	}
+}
+
+func (c *capturingLogger) Debug(format interface{}, vars ...interface{}) {
+	c.buffer.writeLine("debug", formatPrintf(format, vars...))
+	c.Logger.Debug(format, vars...)
+}
+
+func (c *capturingLogger) Info(format interface{}, vars ...interface{}) {
+	c.buffer.writeLine("info", formatPrintf(format, vars...))
+	c.Logger.Info(format, vars...)
+}
+
+func (c *capturingLogger) Warn(format interface{}, vars ...interface{}) {
+	c.buffer.writeLine("warn", formatPrintf(format, vars...))
+	c.Logger.Warn(format, vars...)
+}
+
+func (c *capturingLogger) Error(format interface{}, vars ...interface{}) {
+	c.buffer.writeLine("error", formatPrintf(format, vars...))
+	c.Logger.Error(format, vars...)
+}
+
+func (c *capturingLogger) DebugWith(format interface{}, vars ...interface{}) {
+	c.buffer.writeLine("debug", formatWith(format, vars...))
+	c.Logger.DebugWith(format, vars...)
+}
+
+func (c *capturingLogger) InfoWith(format interface{}, vars ...interface{}) {
+	c.buffer.writeLine("info", formatWith(format, vars...))
+	c.Logger.InfoWith(format, vars...)
+}
+
+func (c *capturingLogger) WarnWith(format interface{}, vars ...interface{}) {
+	c.buffer.writeLine("warn", formatWith(format, vars...))
+	c.Logger.WarnWith(format, vars...)
+}
+
+func (c *capturingLogger) ErrorWith(format interface{}, vars ...interface{}) {
+	c.buffer.writeLine("error", formatWith(format, vars...))
+	c.Logger.ErrorWith(format, vars...)
+}
+
+// formatPrintf renders a printf-style Debug/Info/Warn/Error call the way the wrapped logger does.
+func formatPrintf(format interface{}, vars ...interface{}) string {
+	return fmt.Sprintf(fmt.Sprint(format), vars...)
+}
+
+// formatWith renders a DebugWith/InfoWith/WarnWith/ErrorWith call's alternating key/value vars
+// as "message key1=value1 key2=value2 ...", matching the repo's structured logging convention
+// (e.g. trigger.go's k.Logger.WarnWith("Failed to persist job checkpoint", "error", err)).
+func formatWith(format interface{}, vars ...interface{}) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprint(format))
+
+	i := 0
+	for ; i+1 < len(vars); i += 2 {
+		fmt.Fprintf(&b, " %v=%v", vars[i], vars[i+1])
+	}
+	if i < len(vars) {
+		fmt.Fprintf(&b, " %v=<missing>", vars[i])
+	}
+
+	return b.String()
+}
+
+func (c *capturingLogger) GetChild(name string) logger.Logger {
+	return newCapturingLogger(c.Logger.GetChild(name), c.buffer)
+}
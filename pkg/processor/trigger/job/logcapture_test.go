@@ -0,0 +1,63 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package job
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nuclio/logger"
+)
+
+func TestRunLogBufferWritesLines(t *testing.T) {
+	buffer := newRunLogBuffer(0)
+
+	buffer.writeLine("info", "hello world")
+
+	content := string(buffer.Bytes())
+	if !strings.Contains(content, "[info] hello world") {
+		t.Errorf("expected buffer to contain the formatted line, got %q", content)
+	}
+}
+
+func TestRunLogBufferTruncatesAtMaxSize(t *testing.T) {
+	buffer := newRunLogBuffer(10)
+
+	buffer.writeLine("info", "a fairly long line that exceeds the cap")
+	buffer.writeLine("info", "a second line")
+
+	content := string(buffer.Bytes())
+	if !strings.Contains(content, "truncated") {
+		t.Errorf("expected buffer to be truncated, got %q", content)
+	}
+	if strings.Contains(content, "second line") {
+		t.Error("expected no lines to be appended after truncation")
+	}
+}
+
+// fakeLogger is a minimal stand-in for logger.Logger: it implements only WarnWith, the one
+// method these tests reach, so the embedded nil interface is never called.
+type fakeLogger struct {
+	logger.Logger
+}
+
+func (f *fakeLogger) WarnWith(format interface{}, vars ...interface{}) {}
+
+func TestCapturingLoggerFormatsWithCallsAsKeyValuePairs(t *testing.T) {
+	buffer := newRunLogBuffer(0)
+	capturing := newCapturingLogger(&fakeLogger{}, buffer)
+
+	capturing.WarnWith("Failed to persist job checkpoint", "error", "boom")
+
+	content := string(buffer.Bytes())
+	if !strings.Contains(content, "Failed to persist job checkpoint error=boom") {
+		t.Errorf("expected buffer to contain key=value formatted vars, got %q", content)
+	}
+	if strings.Contains(content, "%!") {
+		t.Errorf("expected no printf error markers in *With output, got %q", content)
+	}
+}
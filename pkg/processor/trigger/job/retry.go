@@ -0,0 +1,68 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package job
+
+import (
+	"math/rand"
+	"time"
+)
+
+// isRetriable reports whether result falls into one of the configured RetryOn categories.
+func (k *job) isRetriable(result *CompletionResult) bool {
+	for _, condition := range k.configuration.RetryOn {
+		switch condition {
+		case RetryOnSubmitError:
+			if result.Stage == CompletionStageSubmit {
+				return true
+			}
+		case RetryOnProcessError:
+			if result.Stage == CompletionStageProcess {
+				return true
+			}
+		case RetryOnServerErrorResponse:
+			if result.Stage == CompletionStageResponse && result.HTTPStatusCode >= 500 && result.HTTPStatusCode < 600 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// nextBackoff grows the current backoff by the configured multiplier, capped at MaxBackoff.
+func (k *job) nextBackoff(current time.Duration) time.Duration {
+	next := time.Duration(float64(current) * k.configuration.BackoffMultiplier)
+	if k.configuration.MaxBackoff > 0 && next > k.configuration.MaxBackoff {
+		next = k.configuration.MaxBackoff
+	}
+
+	return next
+}
+
+// withJitter randomizes d to a value in [d/2, d] to avoid retry storms across many concurrently
+// scheduled job triggers.
+func withJitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return d
+	}
+
+	half := int64(d) / 2
+
+	// #nosec G404 -- jitter does not need to be cryptographically secure
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+// waitBackoff blocks for the (jittered) backoff duration, returning false early if the trigger
+// was stopped in the meantime.
+func (k *job) waitBackoff(backoff time.Duration) bool {
+	select {
+	case <-time.After(withJitter(backoff)):
+		return true
+	case <-k.stopChan:
+		return false
+	}
+}
@@ -0,0 +1,65 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package job
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestJob(retryOn []RetryCondition) *job {
+	return &job{
+		configuration: &Configuration{
+			BackoffMultiplier: 2.0,
+			MaxBackoff:        10 * time.Second,
+			RetryOn:           retryOn,
+		},
+		stopChan:   make(chan struct{}),
+		checkpoint: newCheckpointState(),
+	}
+}
+
+func TestIsRetriableMatchesConfiguredConditions(t *testing.T) {
+	k := newTestJob([]RetryCondition{RetryOnSubmitError, RetryOnServerErrorResponse})
+
+	if !k.isRetriable(&CompletionResult{Stage: CompletionStageSubmit}) {
+		t.Error("expected submit errors to be retriable")
+	}
+	if k.isRetriable(&CompletionResult{Stage: CompletionStageProcess}) {
+		t.Error("expected process errors not to be retriable when not configured")
+	}
+	if !k.isRetriable(&CompletionResult{Stage: CompletionStageResponse, HTTPStatusCode: 503}) {
+		t.Error("expected 5xx responses to be retriable")
+	}
+	if k.isRetriable(&CompletionResult{Stage: CompletionStageResponse, HTTPStatusCode: 404}) {
+		t.Error("expected 4xx responses not to be retriable")
+	}
+}
+
+func TestNextBackoffGrowsAndCaps(t *testing.T) {
+	k := newTestJob(nil)
+
+	backoff := time.Second
+	backoff = k.nextBackoff(backoff)
+	if backoff != 2*time.Second {
+		t.Errorf("expected 2s, got %s", backoff)
+	}
+
+	backoff = k.nextBackoff(9 * time.Second)
+	if backoff != 10*time.Second {
+		t.Errorf("expected backoff to cap at 10s, got %s", backoff)
+	}
+}
+
+func TestWaitBackoffReturnsFalseOnStop(t *testing.T) {
+	k := newTestJob(nil)
+	close(k.stopChan)
+
+	if k.waitBackoff(time.Minute) {
+		t.Error("expected waitBackoff to return false once stopped")
+	}
+}
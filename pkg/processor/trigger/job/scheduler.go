@@ -0,0 +1,141 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package job
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// runScheduler computes successive fire times from the configured cron Schedule and launches
+// one run per tick through the existing worker allocator, honoring MaxRuns and ConcurrencyPolicy.
+// It returns (and the trigger stops scheduling) once the schedule can't be parsed, Stop is called,
+// or MaxRuns scheduled runs have been launched.
+func (k *job) runScheduler() {
+	schedule, err := cron.ParseStandard(k.configuration.Schedule)
+	if err != nil {
+		k.Logger.WarnWith("Failed to parse job schedule, periodic execution disabled",
+			"schedule", k.configuration.Schedule,
+			"error", err)
+		return
+	}
+
+	runsLaunched, next := k.resumeScheduleCursor(schedule)
+
+	for {
+		select {
+		case <-k.stopChan:
+			return
+		case <-time.After(time.Until(next) + k.jitter()):
+		}
+
+		if k.configuration.MaxRuns > 0 && runsLaunched >= k.configuration.MaxRuns {
+			return
+		}
+
+		if k.shouldStartRun() {
+			k.startRun(k.nextRunID())
+			runsLaunched++
+		} else {
+			k.Logger.DebugWith("Skipping scheduled run, previous run still in flight",
+				"concurrencyPolicy", k.configuration.ConcurrencyPolicy)
+		}
+
+		next = schedule.Next(next)
+		k.updateScheduleCheckpoint(next, runsLaunched)
+	}
+}
+
+// resumeScheduleCursor picks up a periodic schedule's cursor from the loaded checkpoint, if
+// the processor restarted mid-schedule, or computes a fresh one otherwise. A resumed cursor
+// that is already past is advanced to the next fire time after now, so ticks missed while
+// the processor was down are skipped.
+func (k *job) resumeScheduleCursor(schedule cron.Schedule) (int, time.Time) {
+	k.checkpointMu.Lock()
+	defer k.checkpointMu.Unlock()
+
+	if !k.checkpoint.ScheduleCursor.IsZero() {
+		next := k.checkpoint.ScheduleCursor
+		if now := time.Now(); next.Before(now) {
+			next = schedule.Next(now)
+		}
+		return k.checkpoint.RunsLaunched, next
+	}
+
+	return 0, schedule.Next(time.Now())
+}
+
+// jitter returns a random delay in [0, Jitter) to spread out scheduled fire times.
+func (k *job) jitter() time.Duration {
+	if k.configuration.Jitter <= 0 {
+		return 0
+	}
+
+	// #nosec G404 -- jitter does not need to be cryptographically secure
+	return time.Duration(rand.Int63n(int64(k.configuration.Jitter)))
+}
+
+// shouldStartRun applies ConcurrencyPolicy against the currently in-flight runs, cooperatively
+// cancelling them first under Replace.
+func (k *job) shouldStartRun() bool {
+	switch k.configuration.ConcurrencyPolicy {
+	case ConcurrencyPolicyForbid:
+		return k.inFlightCount() == 0
+	case ConcurrencyPolicyReplace:
+		k.cancelInFlightRuns()
+		return true
+	default:
+		return true
+	}
+}
+
+// startRun launches a fresh run under its own cancellable context, tracking it as in-flight
+// until it completes.
+func (k *job) startRun(runID string) {
+	k.startRunResumed(runID, 0, k.configuration.InitialBackoff)
+}
+
+// startRunResumed launches a run starting from a resumed attempt number and backoff cursor,
+// used to pick a retry sequence back up after a processor restart.
+func (k *job) startRunResumed(runID string, resumeAttempt int, resumeBackoff time.Duration) {
+	ctx, cancel := context.WithCancel(context.Background())
+	k.inFlightRuns.Store(runID, cancel)
+	k.recordInFlightRun(runID)
+
+	k.wg.Add(1)
+	go func() {
+		defer k.wg.Done()
+		defer k.inFlightRuns.Delete(runID)
+		defer k.clearInFlightRun(runID)
+		defer cancel()
+
+		k.handleEvent(ctx, runID, resumeAttempt, resumeBackoff)
+	}()
+}
+
+// cancelInFlightRuns cooperatively cancels every tracked in-flight run. Cancellation is
+// best-effort: a run already inside AllocateWorkerAndSubmitEvent completes that attempt but
+// will not be retried.
+func (k *job) cancelInFlightRuns() {
+	k.inFlightRuns.Range(func(_, value interface{}) bool {
+		value.(context.CancelFunc)()
+		return true
+	})
+}
+
+func (k *job) inFlightCount() int {
+	count := 0
+	k.inFlightRuns.Range(func(_, _ interface{}) bool {
+		count++
+		return true
+	})
+
+	return count
+}
@@ -0,0 +1,74 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package job
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+)
+
+func TestShouldStartRunForbidSkipsWhileInFlight(t *testing.T) {
+	k := newTestJob(nil)
+	k.configuration.ConcurrencyPolicy = ConcurrencyPolicyForbid
+
+	_, cancel := context.WithCancel(context.Background())
+	k.inFlightRuns.Store("run-1", cancel)
+	defer cancel()
+
+	if k.shouldStartRun() {
+		t.Error("expected Forbid to skip a new run while one is in flight")
+	}
+}
+
+func TestShouldStartRunReplaceCancelsInFlight(t *testing.T) {
+	k := newTestJob(nil)
+	k.configuration.ConcurrencyPolicy = ConcurrencyPolicyReplace
+
+	ctx, cancel := context.WithCancel(context.Background())
+	k.inFlightRuns.Store("run-1", cancel)
+
+	if !k.shouldStartRun() {
+		t.Error("expected Replace to allow a new run")
+	}
+	if ctx.Err() == nil {
+		t.Error("expected Replace to cancel the previous in-flight run")
+	}
+}
+
+func TestNextRunIDIsUnique(t *testing.T) {
+	k := newTestJob(nil)
+
+	first := k.nextRunID()
+	second := k.nextRunID()
+	if first == second {
+		t.Errorf("expected distinct run ids, got %q twice", first)
+	}
+}
+
+func TestNextRunIDResumesPastPriorRunsAfterRestart(t *testing.T) {
+	before := newTestJob(nil)
+
+	launched := map[string]bool{
+		before.nextRunID(): true,
+		before.nextRunID(): true,
+		before.nextRunID(): true,
+	}
+
+	// Simulate a processor restart: a fresh job instance seeded from the checkpoint the
+	// previous instance would have persisted.
+	state := newCheckpointState()
+	state.RunSeq = atomic.LoadInt64(&before.runSeq)
+
+	after := newTestJob(nil)
+	after.seedRunSeq(state)
+
+	resumed := after.nextRunID()
+	if launched[resumed] {
+		t.Errorf("expected resumed run id %q to not collide with a run id launched before the restart", resumed)
+	}
+}
@@ -7,11 +7,14 @@ SPDX-License-Identifier: Apache-2.0
 package job
 
 import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/nuclio/errors"
 	"github.com/nuclio/logger"
-	"github.com/nuclio/nuclio-sdk-go"
 	"github.com/nuclio/nuclio/pkg/common"
 	"github.com/nuclio/nuclio/pkg/functionconfig"
 	"github.com/nuclio/nuclio/pkg/processor/controlcommunication"
@@ -22,6 +25,33 @@ import (
 type job struct {
 	trigger.AbstractTrigger
 	configuration *Configuration
+	stopChan      chan struct{}
+
+	// wg tracks in-flight runs so Stop can drain them before returning.
+	wg sync.WaitGroup
+
+	// inFlightRuns maps a runID to the context.CancelFunc that cooperatively cancels it,
+	// populated for every run launched, one-shot or scheduled.
+	inFlightRuns sync.Map
+
+	// runSeq is the in-memory counter nextRunID draws from. It is seeded from the loaded
+	// checkpoint's RunSeq in Start, so resumed runIDs never collide with ones already handed
+	// out before a processor restart.
+	runSeq int64
+
+	// archiveSink publishes per-run log archives, or nil when log archiving is disabled.
+	archiveSink archiveSink
+
+	// artifactSink registers produced outputs with an external artifact service, or nil when
+	// output-artifact recording is disabled.
+	artifactSink ArtifactSink
+
+	// checkpointStore persists checkpoint across processor restarts, or nil to rely solely on
+	// the functionconfig.Checkpoint threaded through Start/Stop.
+	checkpointStore CheckpointStore
+
+	checkpointMu sync.Mutex
+	checkpoint   *checkpointState
 }
 
 func newTrigger(logger logger.Logger,
@@ -40,9 +70,29 @@ func newTrigger(logger logger.Logger,
 		return nil, errors.New("Failed to create abstract trigger")
 	}
 
+	sink, err := newArchiveSink(logger, configuration.LogArchive)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create log archive sink")
+	}
+
+	artifactSink, err := newArtifactSink(configuration.Artifacts)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create artifact sink")
+	}
+
+	var checkpointStore CheckpointStore
+	if configuration.CheckpointFilePath != "" {
+		checkpointStore = newFileCheckpointStore(configuration.CheckpointFilePath)
+	}
+
 	newTrigger := job{
 		AbstractTrigger: abstractTrigger,
 		configuration:   configuration,
+		stopChan:        make(chan struct{}),
+		archiveSink:     sink,
+		artifactSink:    artifactSink,
+		checkpointStore: checkpointStore,
+		checkpoint:      newCheckpointState(),
 	}
 	newTrigger.AbstractTrigger.Trigger = &newTrigger
 
@@ -52,36 +102,475 @@ func newTrigger(logger logger.Logger,
 func (k *job) Start(checkpoint functionconfig.Checkpoint) error {
 	k.Logger.DebugWith("Starting job")
 
-	go k.handleEvent()
+	state, err := k.loadCheckpoint(checkpoint)
+	if err != nil {
+		return errors.Wrap(err, "Failed to load job checkpoint")
+	}
+	k.checkpointMu.Lock()
+	k.checkpoint = state
+	k.checkpointMu.Unlock()
+
+	k.seedRunSeq(state)
+
+	k.reemitUnacknowledgedTerminalRuns(state)
+	k.finalizeLostInFlightRuns(state)
+
+	if k.configuration.Schedule != "" {
+		go k.runScheduler()
+	} else if runID, alreadyCompleted := k.lastCompletedRunID(state); alreadyCompleted {
+		k.Logger.InfoWith("Skipping already-completed one-shot run", "runID", runID)
+	} else if state.Attempt == 0 && state.BackoffCursor == 0 {
+		k.startRun(k.nextRunID())
+	} else {
+		k.startRunResumed(k.nextRunID(), state.Attempt, state.BackoffCursor)
+	}
 
 	return nil
 }
 
-func (k *job) handleEvent() {
-	response, submitError, processError := k.AllocateWorkerAndSubmitEvent( // nolint: errcheck
-		&k.configuration.Event,
-		k.Logger,
-		10*time.Second)
-	hasErr := submitError != nil || processError != nil
+// loadCheckpoint reads the trigger's checkpoint either from the configured CheckpointStore, or
+// by decoding the functionconfig.Checkpoint the runtime handed to Start.
+func (k *job) loadCheckpoint(checkpoint functionconfig.Checkpoint) (*checkpointState, error) {
+	if k.checkpointStore != nil {
+		return k.checkpointStore.Load(k.GetID())
+	}
+
+	return decodeCheckpoint(checkpoint)
+}
+
+// reemitUnacknowledgedTerminalRuns re-sends the complete control message for any run recorded
+// in state as finished but not yet acknowledged, e.g. because the processor restarted between
+// the run finishing and its control message being sent.
+func (k *job) reemitUnacknowledgedTerminalRuns(state *checkpointState) {
+	for runID, run := range state.TerminalRuns {
+		if run.Acknowledged {
+			continue
+		}
+
+		k.Logger.InfoWith("Re-emitting terminal control message for unacknowledged run", "runID", runID)
+		k.sendCompleteControlMessage(runID, run.Result, run.Attempts, run.ArchiveURL, run.Artifacts)
+		run.Acknowledged = true
+	}
+
+	k.persistCheckpoint()
+}
+
+// finalizeLostInFlightRuns reports a terminal, failed result for every run recorded in state as
+// in flight but not yet terminal, e.g. because the processor crashed instead of stopping
+// cleanly and never got the chance to record the run's real outcome. Without this, such a run
+// would be tracked only by the in-memory inFlightRuns map and vanish across the restart.
+func (k *job) finalizeLostInFlightRuns(state *checkpointState) {
+	for _, runID := range lostInFlightRunIDs(state) {
+		k.Logger.WarnWith("Run was in flight when the processor last stopped, reporting it lost", "runID", runID)
+
+		result := &CompletionResult{
+			Stage:   CompletionStageLost,
+			Message: "Run was in flight when the processor restarted and its outcome is unknown",
+		}
+		k.finalizeRun(runID, result, 0, "", nil)
+	}
+}
+
+// lostInFlightRunIDs returns the runIDs state recorded as in flight but for which no terminal
+// result was ever recorded, i.e. runs whose outcome was lost when the processor crashed.
+func lostInFlightRunIDs(state *checkpointState) []string {
+	var lost []string
+	for runID := range state.InFlightRuns {
+		if _, ok := state.TerminalRuns[runID]; !ok {
+			lost = append(lost, runID)
+		}
+	}
+
+	return lost
+}
+
+// seedRunSeq restores the run id sequence counter from a loaded checkpoint, so that a
+// resumed trigger never hands out a runID that was already used (and possibly still unacknowledged
+// or in flight) before the processor restarted.
+func (k *job) seedRunSeq(state *checkpointState) {
+	atomic.StoreInt64(&k.runSeq, state.RunSeq)
+}
+
+// nextRunID returns a stable, monotonically increasing identifier for a single run, attached
+// to every control message that run emits. The sequence counter is persisted to the checkpoint
+// as part of handing out the id, so a restart resumes it via seedRunSeq rather than starting over.
+func (k *job) nextRunID() string {
+	seq := atomic.AddInt64(&k.runSeq, 1)
+
+	k.checkpointMu.Lock()
+	k.checkpoint.RunSeq = seq
+	state := k.checkpoint.clone()
+	k.checkpointMu.Unlock()
+
+	k.persistCheckpointState(state)
 
-	switch typedResponse := response.(type) {
-	case nuclio.Response:
-		hasErr = hasErr || typedResponse.StatusCode != 200
-	default:
+	return fmt.Sprintf("%s-%d", k.GetID(), seq)
+}
+
+// lastCompletedRunID returns the runID nextRunID last handed out (if any) and whether a
+// terminal result for it is already recorded in state. A one-shot trigger resuming after its
+// single run already finished must not launch a duplicate of it.
+func (k *job) lastCompletedRunID(state *checkpointState) (string, bool) {
+	if state.RunSeq == 0 {
+		return "", false
 	}
 
+	runID := fmt.Sprintf("%s-%d", k.GetID(), state.RunSeq)
+	_, completed := state.TerminalRuns[runID]
+
+	return runID, completed
+}
+
+func (k *job) handleEvent(ctx context.Context, runID string, resumeAttempt int, resumeBackoff time.Duration) {
+	runStart := time.Now()
+	logBuffer := newRunLogBuffer(k.configuration.LogArchive.MaxSizeBytes)
+	runLogger := newCapturingLogger(k.Logger, logBuffer)
+
+	backoff := resumeBackoff
+	var completionResult *CompletionResult
+	var lastResponse interface{}
+	attempt := resumeAttempt
+
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		attempt++
+		startTime := time.Now()
+
+		response, submitError, processError := k.AllocateWorkerAndSubmitEvent( // nolint: errcheck
+			&k.configuration.Event,
+			runLogger,
+			k.configuration.AllocationTimeout)
+		lastResponse = response
+
+		completionResult = newCompletionResult(response,
+			submitError,
+			processError,
+			k.GetID(),
+			time.Since(startTime),
+			attempt)
+
+		k.updateAttemptCheckpoint(runID, attempt, backoff)
+
+		if completionResult == nil || attempt >= k.configuration.MaxAttempts || !k.isRetriable(completionResult) {
+			break
+		}
+
+		k.sendAttemptControlMessage(runID, completionResult)
+
+		if !k.waitBackoff(backoff) {
+			return
+		}
+		backoff = k.nextBackoff(backoff)
+	}
+
+	archiveURL := k.publishRunArchive(runID, completionResult, attempt, runStart, logBuffer)
+	artifacts := k.recordArtifacts(runID, lastResponse)
+
+	k.finalizeRun(runID, completionResult, attempt, archiveURL, artifacts)
+}
+
+// updateAttemptCheckpoint records how far the current run's retry sequence has gotten, so a
+// processor restart mid-retry resumes from this attempt and backoff rather than from scratch.
+func (k *job) updateAttemptCheckpoint(runID string, attempt int, backoffCursor time.Duration) {
+	k.checkpointMu.Lock()
+	k.checkpoint.Attempt = attempt
+	k.checkpoint.LastSubmittedEventID = runID
+	k.checkpoint.BackoffCursor = backoffCursor
+	state := k.checkpoint.clone()
+	k.checkpointMu.Unlock()
+
+	k.persistCheckpointState(state)
+}
+
+// finalizeRun records the run as terminal-but-unacknowledged, sends its complete control
+// message, and only then marks it acknowledged. A crash between those two steps leaves the
+// checkpoint showing an unacknowledged run, which Start re-emits on the next resume.
+func (k *job) finalizeRun(runID string, result *CompletionResult, attempts int, archiveURL string, artifacts []recordedArtifact) {
+	k.recordTerminalRun(runID, result, attempts, archiveURL, artifacts, false)
+	k.sendCompleteControlMessage(runID, result, attempts, archiveURL, artifacts)
+	k.recordTerminalRun(runID, result, attempts, archiveURL, artifacts, true)
+}
+
+func (k *job) recordTerminalRun(runID string,
+	result *CompletionResult,
+	attempts int,
+	archiveURL string,
+	artifacts []recordedArtifact,
+	acknowledged bool) {
+
+	k.checkpointMu.Lock()
+	k.checkpoint.TerminalRuns[runID] = &terminalRun{
+		Result:       result,
+		Attempts:     attempts,
+		ArchiveURL:   archiveURL,
+		Artifacts:    artifacts,
+		Acknowledged: acknowledged,
+	}
+	delete(k.checkpoint.InFlightRuns, runID)
+	state := k.checkpoint.clone()
+	k.checkpointMu.Unlock()
+
+	k.persistCheckpointState(state)
+}
+
+// recordInFlightRun marks runID as in flight in the checkpoint, so that if the processor
+// crashes before the run finalizes, finalizeLostInFlightRuns can detect and report it on the
+// next resume instead of silently losing track of it.
+func (k *job) recordInFlightRun(runID string) {
+	k.checkpointMu.Lock()
+	k.checkpoint.InFlightRuns[runID] = time.Now().UTC()
+	state := k.checkpoint.clone()
+	k.checkpointMu.Unlock()
+
+	k.persistCheckpointState(state)
+}
+
+// clearInFlightRun removes runID from the checkpoint's in-flight set. recordTerminalRun already
+// does this for a run that finalizes normally; this covers a run whose context was cancelled
+// (e.g. Stop(force) or a Replace concurrency policy) before it reached finalizeRun.
+func (k *job) clearInFlightRun(runID string) {
+	k.checkpointMu.Lock()
+	delete(k.checkpoint.InFlightRuns, runID)
+	state := k.checkpoint.clone()
+	k.checkpointMu.Unlock()
+
+	k.persistCheckpointState(state)
+}
+
+// updateScheduleCheckpoint records a periodic schedule's cursor after computing the next fire
+// time, so a processor restart resumes the schedule rather than re-running missed ticks.
+func (k *job) updateScheduleCheckpoint(next time.Time, runsLaunched int) {
+	k.checkpointMu.Lock()
+	k.checkpoint.ScheduleCursor = next
+	k.checkpoint.RunsLaunched = runsLaunched
+	state := k.checkpoint.clone()
+	k.checkpointMu.Unlock()
+
+	k.persistCheckpointState(state)
+}
+
+// persistCheckpointState saves state to the configured CheckpointStore, if any. When no store
+// is configured, the trigger relies on Stop's return value instead.
+func (k *job) persistCheckpointState(state *checkpointState) {
+	if k.checkpointStore == nil {
+		return
+	}
+
+	if err := k.checkpointStore.Save(k.GetID(), state); err != nil {
+		k.Logger.WarnWith("Failed to persist job checkpoint", "error", err)
+	}
+}
+
+// persistCheckpoint snapshots and persists the current checkpoint.
+func (k *job) persistCheckpoint() {
+	k.checkpointMu.Lock()
+	state := k.checkpoint.clone()
+	k.checkpointMu.Unlock()
+
+	k.persistCheckpointState(state)
+}
+
+// recordArtifacts inspects response for an X-Nuclio-Artifacts manifest, validates it against
+// the declared Artifacts.Outputs, and registers each matching entry with the configured
+// ArtifactSink. A failure to parse the manifest or register any single artifact is logged and
+// otherwise ignored: it must never fail the run itself.
+func (k *job) recordArtifacts(runID string, response interface{}) []recordedArtifact {
+	if k.artifactSink == nil {
+		return nil
+	}
+
+	entries, err := extractArtifactManifest(response)
+	if err != nil {
+		k.Logger.WarnWith("Failed to parse artifact manifest", "runID", runID, "error", err)
+		return nil
+	}
+
+	declared := declaredArtifactsByName(k.configuration.Artifacts.Outputs)
+	project, domain, version := k.projectDomainVersion()
+
+	var recorded []recordedArtifact
+	for _, entry := range entries {
+		spec, ok := declared[entry.Name]
+		if !ok {
+			k.Logger.WarnWith("Ignoring undeclared artifact", "runID", runID, "name", entry.Name)
+			continue
+		}
+
+		if err := validateArtifactChecksum(spec, entry); err != nil {
+			k.Logger.WarnWith("Ignoring artifact failing checksum validation", "runID", runID, "name", entry.Name, "error", err)
+			continue
+		}
+
+		artifactID, err := k.artifactSink.Register(project, domain, version, entry)
+		if err != nil {
+			k.Logger.WarnWith("Failed to register artifact", "runID", runID, "name", entry.Name, "error", err)
+			continue
+		}
+
+		recorded = append(recorded, recordedArtifact{
+			Name:       entry.Name,
+			URI:        entry.URI,
+			ArtifactID: artifactID,
+		})
+	}
+
+	return recorded
+}
+
+// projectDomainVersion derives the project/domain/version triple artifacts are registered
+// under from the runtime's function configuration.
+func (k *job) projectDomainVersion() (project, domain, version string) {
+	meta := k.configuration.RuntimeConfiguration.FunctionConfig.Meta
+
+	project = meta.Labels["nuclio.io/project-name"]
+	domain = meta.Namespace
+	version = meta.Labels["nuclio.io/function-version"]
+
+	return project, domain, version
+}
+
+// maxArchivePublishAttempts bounds how many times publishRunArchive retries a failed Publish
+// call, backing off between attempts to apply back-pressure against a temporarily unavailable
+// archive sink instead of dropping the archive on its first hiccup.
+const maxArchivePublishAttempts = 3
+
+// publishRunArchive packages the run's manifest and captured log into a .tar.gz and publishes it
+// to the configured sink, retrying with backoff on failure. A failure that persists through
+// maxArchivePublishAttempts (or a build failure) is logged and otherwise ignored: it must never
+// fail the run itself.
+func (k *job) publishRunArchive(runID string,
+	result *CompletionResult,
+	attempts int,
+	runStart time.Time,
+	logBuffer *runLogBuffer) string {
+
+	if k.archiveSink == nil {
+		return ""
+	}
+
+	manifest := runManifest{
+		RunID:     runID,
+		TriggerID: k.GetID(),
+		Attempts:  attempts,
+		StartTime: runStart.UTC(),
+		Elapsed:   time.Since(runStart),
+		Result:    result,
+	}
+
+	archive, err := buildRunArchive(manifest, logBuffer.Bytes())
+	if err != nil {
+		k.Logger.WarnWith("Failed to build run log archive", "runID", runID, "error", err)
+		return ""
+	}
+
+	maxSize := k.configuration.LogArchive.MaxSizeBytes
+	if maxSize > 0 && int64(len(archive)) > maxSize {
+		k.Logger.WarnWith("Run log archive exceeds size cap, skipping publish",
+			"runID", runID,
+			"size", len(archive),
+			"maxSize", maxSize)
+		return ""
+	}
+
+	archiveURL, err := k.publishArchiveWithBackoff(runID, archive)
+	if err != nil {
+		k.Logger.WarnWith("Failed to publish run log archive", "runID", runID, "error", err)
+		return ""
+	}
+
+	return archiveURL
+}
+
+// publishArchiveWithBackoff retries archiveSink.Publish up to maxArchivePublishAttempts times,
+// backing off between attempts the same way a retried event submission does.
+func (k *job) publishArchiveWithBackoff(runID string, archive []byte) (string, error) {
+	backoff := k.configuration.InitialBackoff
+
+	var lastErr error
+	for attempt := 1; attempt <= maxArchivePublishAttempts; attempt++ {
+		archiveURL, err := k.archiveSink.Publish(runID, archive)
+		if err == nil {
+			return archiveURL, nil
+		}
+		lastErr = err
+
+		if attempt == maxArchivePublishAttempts {
+			break
+		}
+
+		k.Logger.WarnWith("Failed to publish run log archive, retrying",
+			"runID", runID, "attempt", attempt, "error", err)
+
+		if !k.waitBackoff(backoff) {
+			return "", err
+		}
+		backoff = k.nextBackoff(backoff)
+	}
+
+	return "", lastErr
+}
+
+func (k *job) sendAttemptControlMessage(runID string, result *CompletionResult) {
 	controlMessage := &controlcommunication.ControlMessage{
-		Kind: controlcommunication.ControlMessageKind("complete"),
+		Kind: controlcommunication.ControlMessageKind("attempt"),
 		Attributes: map[string]interface{}{
-			"status": hasErr,
+			"runID":  runID,
+			"result": result,
 		},
 	}
 
 	k.configuration.RuntimeConfiguration.ControlMessageBroker.SendToConsumers(controlMessage)
 }
 
+func (k *job) sendCompleteControlMessage(runID string,
+	result *CompletionResult,
+	attempts int,
+	archiveURL string,
+	artifacts []recordedArtifact) {
+
+	attributes := map[string]interface{}{
+		// kept for backward compatibility: true when the run failed, regardless of stage
+		"status":   result != nil,
+		"attempts": attempts,
+		"runID":    runID,
+	}
+	if result != nil {
+		attributes["result"] = result
+	}
+	if archiveURL != "" {
+		attributes["logArchiveURL"] = archiveURL
+	}
+	if len(artifacts) > 0 {
+		attributes["artifacts"] = artifacts
+	}
+
+	controlMessage := &controlcommunication.ControlMessage{
+		Kind:       controlcommunication.ControlMessageKind("complete"),
+		Attributes: attributes,
+	}
+
+	k.configuration.RuntimeConfiguration.ControlMessageBroker.SendToConsumers(controlMessage)
+}
+
 func (k *job) Stop(force bool) (functionconfig.Checkpoint, error) {
-	return nil, nil
+	close(k.stopChan)
+
+	if force {
+		k.cancelInFlightRuns()
+	}
+
+	k.wg.Wait()
+
+	k.checkpointMu.Lock()
+	state := k.checkpoint.clone()
+	k.checkpointMu.Unlock()
+
+	k.persistCheckpointState(state)
+
+	return encodeCheckpoint(state)
 }
 
 func (k *job) GetConfig() map[string]interface{} {
@@ -0,0 +1,106 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package job
+
+import (
+	"errors"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestLastCompletedRunIDFreshCheckpointIsNeverCompleted(t *testing.T) {
+	k := newTestJob(nil)
+
+	runID, completed := k.lastCompletedRunID(newCheckpointState())
+	if completed {
+		t.Errorf("expected a fresh checkpoint to report no completed run, got %q", runID)
+	}
+}
+
+func TestLastCompletedRunIDMatchesLastHandedOutID(t *testing.T) {
+	k := newTestJob(nil)
+
+	runID := k.nextRunID()
+
+	k.checkpoint.TerminalRuns[runID] = &terminalRun{Acknowledged: true}
+
+	gotRunID, completed := k.lastCompletedRunID(k.checkpoint)
+	if !completed {
+		t.Fatalf("expected %q to be reported as completed", runID)
+	}
+	if gotRunID != runID {
+		t.Errorf("expected last completed run id %q, got %q", runID, gotRunID)
+	}
+}
+
+func TestLostInFlightRunIDsExcludesFinalizedRuns(t *testing.T) {
+	state := newCheckpointState()
+	state.InFlightRuns["run-1"] = time.Unix(1700000000, 0).UTC()
+	state.InFlightRuns["run-2"] = time.Unix(1700000001, 0).UTC()
+	state.TerminalRuns["run-2"] = &terminalRun{Acknowledged: true}
+
+	lost := lostInFlightRunIDs(state)
+	sort.Strings(lost)
+
+	if len(lost) != 1 || lost[0] != "run-1" {
+		t.Errorf("expected only run-1 to be reported lost, got %v", lost)
+	}
+}
+
+func TestLostInFlightRunIDsNoneInFlight(t *testing.T) {
+	state := newCheckpointState()
+
+	if lost := lostInFlightRunIDs(state); len(lost) != 0 {
+		t.Errorf("expected no lost runs, got %v", lost)
+	}
+}
+
+// countingFailThenSucceedSink fails its first `failures` Publish calls, then succeeds.
+type countingFailThenSucceedSink struct {
+	failures int
+	calls    int
+}
+
+func (s *countingFailThenSucceedSink) Publish(runID string, archive []byte) (string, error) {
+	s.calls++
+	if s.calls <= s.failures {
+		return "", errors.New("sink unavailable")
+	}
+
+	return "archived://" + runID, nil
+}
+
+func TestPublishArchiveWithBackoffRetriesOnFailure(t *testing.T) {
+	k := newTestJob(nil)
+	sink := &countingFailThenSucceedSink{failures: 2}
+	k.archiveSink = sink
+
+	url, err := k.publishArchiveWithBackoff("run-1", []byte("data"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if url != "archived://run-1" {
+		t.Errorf("expected the eventual successful publish url, got %q", url)
+	}
+	if sink.calls != 3 {
+		t.Errorf("expected 3 attempts before success, got %d", sink.calls)
+	}
+}
+
+func TestPublishArchiveWithBackoffGivesUpAfterMaxAttempts(t *testing.T) {
+	k := newTestJob(nil)
+	sink := &countingFailThenSucceedSink{failures: maxArchivePublishAttempts}
+	k.archiveSink = sink
+
+	if _, err := k.publishArchiveWithBackoff("run-1", []byte("data")); err == nil {
+		t.Error("expected an error once every attempt fails")
+	}
+	if sink.calls != maxArchivePublishAttempts {
+		t.Errorf("expected exactly %d attempts, got %d", maxArchivePublishAttempts, sink.calls)
+	}
+}
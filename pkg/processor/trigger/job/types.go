@@ -0,0 +1,226 @@
+/*
+SPDX-FileCopyrightText: © 2025 DSLab - Fondazione Bruno Kessler
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package job
+
+import (
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/nuclio/errors"
+	"github.com/nuclio/nuclio/pkg/functionconfig"
+	"github.com/nuclio/nuclio/pkg/processor/runtime"
+	"github.com/nuclio/nuclio/pkg/processor/trigger"
+)
+
+// RetryCondition identifies a category of failure that may be retried.
+type RetryCondition string
+
+const (
+	RetryOnSubmitError  RetryCondition = "submit-error"
+	RetryOnProcessError RetryCondition = "process-error"
+
+	// RetryOnServerErrorResponse retries responses whose status code is in the 5xx class.
+	RetryOnServerErrorResponse RetryCondition = "5xx"
+)
+
+const (
+	DefaultMaxAttempts       = 1
+	DefaultInitialBackoff    = time.Second
+	DefaultMaxBackoff        = 30 * time.Second
+	DefaultBackoffMultiplier = 2.0
+	DefaultAllocationTimeout = 10 * time.Second
+)
+
+// ConcurrencyPolicy governs how a periodic job trigger behaves when a scheduled tick fires
+// while a previous run is still in flight, mirroring Kubernetes CronJob semantics.
+type ConcurrencyPolicy string
+
+const (
+	// ConcurrencyPolicyAllow lets runs overlap freely.
+	ConcurrencyPolicyAllow ConcurrencyPolicy = "Allow"
+
+	// ConcurrencyPolicyForbid skips a tick entirely while a previous run is still in flight.
+	ConcurrencyPolicyForbid ConcurrencyPolicy = "Forbid"
+
+	// ConcurrencyPolicyReplace cooperatively cancels in-flight runs before starting the new one.
+	ConcurrencyPolicyReplace ConcurrencyPolicy = "Replace"
+)
+
+const DefaultConcurrencyPolicy = ConcurrencyPolicyAllow
+
+// LogArchiveSinkType identifies where a run's captured log archive is published.
+type LogArchiveSinkType string
+
+const (
+	// LogArchiveSinkNone disables log archive capture and publishing entirely (the default).
+	LogArchiveSinkNone LogArchiveSinkType = ""
+
+	LogArchiveSinkLocal LogArchiveSinkType = "local"
+	LogArchiveSinkHTTP  LogArchiveSinkType = "http"
+	LogArchiveSinkS3    LogArchiveSinkType = "s3"
+)
+
+// LogArchiveConfiguration configures per-run log capture and where the resulting
+// manifest.json + log tarball is published.
+type LogArchiveConfiguration struct {
+	Sink LogArchiveSinkType
+
+	// LocalPath is the directory archives are written to when Sink is "local".
+	LocalPath string
+
+	// HTTPURL is the base URL archives are PUT to when Sink is "http"; the final
+	// object is uploaded to HTTPURL + "/" + runID + ".tar.gz".
+	HTTPURL string
+
+	S3Endpoint        string
+	S3Bucket          string
+	S3Prefix          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	S3UseSSL          bool
+
+	// MaxSizeBytes caps both the captured log buffer and the final archive. Zero means unlimited.
+	MaxSizeBytes int64
+
+	// Retention is how long a published archive should be kept by the sink, if the sink supports it.
+	Retention time.Duration
+}
+
+// ChecksumType identifies how a declared output's integrity is expected to be verified.
+type ChecksumType string
+
+const (
+	ChecksumTypeNone ChecksumType = ""
+	ChecksumTypeMD5  ChecksumType = "md5"
+)
+
+// ArtifactSpec declares a single output a run is expected to produce.
+type ArtifactSpec struct {
+	Name         string
+	URITemplate  string
+	ContentType  string
+	ChecksumType ChecksumType
+}
+
+// ArtifactSinkType identifies the external artifact service outputs are registered with.
+type ArtifactSinkType string
+
+const (
+	// ArtifactSinkTypeNone disables output-artifact recording entirely (the default).
+	ArtifactSinkTypeNone ArtifactSinkType = ""
+
+	ArtifactSinkTypeHTTP ArtifactSinkType = "http"
+	ArtifactSinkTypeS3   ArtifactSinkType = "s3"
+)
+
+// ArtifactsConfiguration declares a run's expected outputs and where they're registered.
+type ArtifactsConfiguration struct {
+	// Outputs declares the outputs a run is expected to produce. A produced artifact whose
+	// name isn't listed here is logged and skipped rather than registered.
+	Outputs []ArtifactSpec
+
+	Sink ArtifactSinkType
+
+	// HTTPURL is the artifact service endpoint outputs are POSTed to when Sink is "http".
+	HTTPURL string
+
+	S3Endpoint        string
+	S3Bucket          string
+	S3Prefix          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	S3UseSSL          bool
+}
+
+// Configuration holds the configuration for a job trigger: the event to submit to a worker,
+// the trigger-wide settings inherited from trigger.Configuration (name, num workers, etc),
+// and the retry-with-backoff policy applied to submission attempts.
+type Configuration struct {
+	trigger.Configuration
+	Event functionconfig.Event
+
+	// MaxAttempts is the maximum number of submission attempts, including the first one. Defaults to 1 (no retry).
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the exponential backoff delay between retries.
+	MaxBackoff time.Duration
+
+	// BackoffMultiplier is applied to the backoff delay after each retry.
+	BackoffMultiplier float64
+
+	// AllocationTimeout bounds how long to wait for a worker to become available for a single attempt.
+	AllocationTimeout time.Duration
+
+	// RetryOn lists the failure categories that should be retried. A failure whose category is not
+	// listed here is treated as terminal, regardless of MaxAttempts.
+	RetryOn []RetryCondition
+
+	// Schedule is a standard cron expression (e.g. "*/5 * * * *"). When set, the trigger runs as a
+	// periodic job instead of firing its configured event exactly once from Start.
+	Schedule string
+
+	// MaxRuns caps the number of scheduled runs. Zero means unlimited.
+	MaxRuns int
+
+	// Jitter adds a random delay, up to this duration, to each scheduled fire time.
+	Jitter time.Duration
+
+	// ConcurrencyPolicy governs overlapping runs when a tick fires while a previous run
+	// is still in flight. Defaults to Allow.
+	ConcurrencyPolicy ConcurrencyPolicy
+
+	// LogArchive configures per-run log capture and archive publishing. Disabled by default.
+	LogArchive LogArchiveConfiguration
+
+	// Artifacts declares a run's expected outputs and where produced artifacts are registered.
+	// Disabled by default.
+	Artifacts ArtifactsConfiguration
+
+	// CheckpointFilePath, when set, persists the trigger's checkpoint to local files under this
+	// directory after each attempt and each scheduled tick. When empty, the trigger relies
+	// instead on the runtime's existing checkpoint mechanism (the functionconfig.Checkpoint
+	// passed to Start and returned from Stop).
+	CheckpointFilePath string
+}
+
+// NewConfiguration parses the trigger configuration attributes into a job Configuration.
+func NewConfiguration(id string,
+	triggerConfiguration *functionconfig.Trigger,
+	runtimeConfiguration *runtime.Configuration) (*Configuration, error) {
+
+	newConfiguration := Configuration{
+		MaxAttempts:       DefaultMaxAttempts,
+		InitialBackoff:    DefaultInitialBackoff,
+		MaxBackoff:        DefaultMaxBackoff,
+		BackoffMultiplier: DefaultBackoffMultiplier,
+		AllocationTimeout: DefaultAllocationTimeout,
+		RetryOn:           []RetryCondition{RetryOnSubmitError, RetryOnProcessError},
+		ConcurrencyPolicy: DefaultConcurrencyPolicy,
+	}
+
+	newTriggerConfiguration, err := trigger.NewConfiguration(id, triggerConfiguration, runtimeConfiguration)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create trigger configuration")
+	}
+	newConfiguration.Configuration = *newTriggerConfiguration
+
+	if err := mapstructure.Decode(triggerConfiguration.Attributes, &newConfiguration); err != nil {
+		return nil, errors.Wrap(err, "Failed to decode trigger attributes")
+	}
+
+	if newConfiguration.MaxAttempts < 1 {
+		newConfiguration.MaxAttempts = DefaultMaxAttempts
+	}
+	if newConfiguration.ConcurrencyPolicy == "" {
+		newConfiguration.ConcurrencyPolicy = DefaultConcurrencyPolicy
+	}
+
+	return &newConfiguration, nil
+}